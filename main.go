@@ -1,17 +1,35 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/jomei/notionapi"
 	"github.com/spf13/cobra"
 )
 
+const (
+	// notionRequestsPerSecond honors Notion's documented ~3 requests/second rate limit.
+	notionRequestsPerSecond = 3
+	maxInsertRetries        = 5
+
+	gotionVersion    = "0.1.0"
+	notionAPIVersion = "2022-06-28"
+	notionAPIBaseURL = "https://api.notion.com/v1"
+)
+
 // RawPageData represents the raw JSON data before converting to Notion API format
 type RawPageData map[string]interface{}
 
@@ -168,28 +186,281 @@ You can use the database ID with or without dashes. The tool will format it corr
 				os.Exit(1)
 			}
 
-			// --- Initialize Notion Client --- 
+			format, _ := cmd.Flags().GetString("format")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			batchSize, _ := cmd.Flags().GetInt("batch-size")
+			failedOutPath, _ := cmd.Flags().GetString("failed-out")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			dryRunOut, _ := cmd.Flags().GetString("dry-run-out")
+
+			switch format {
+			case "json", "csv", "ndjson":
+			default:
+				fmt.Printf("Error: --format must be one of json|csv|ndjson, got %q.\n", format)
+				os.Exit(1)
+			}
+			if concurrency < 1 {
+				concurrency = 1
+			}
+			if batchSize < 1 {
+				batchSize = 1
+			}
+			if dryRun {
+				// Keep the script's request order deterministic and skip rate limiting
+				// for page creates, since none of them reaches Notion (only the
+				// schema fetch below does).
+				concurrency = 1
+			}
+
+			// --- Initialize Notion Client ---
 			client := notionapi.NewClient(notionapi.Token(apiKey))
 			ctx := context.Background()
 
-			// --- Read Data File --- 
-			fmt.Printf("Reading data from %s...\n", dataFile)
-			content, err := os.ReadFile(dataFile)
+			// Fetch database schema up front; CSV coercion and record conversion both need it.
+			database, err := client.Database.Get(ctx, notionapi.DatabaseID(dbID))
+			if err != nil {
+				fmt.Printf("Error accessing database: %v\n", err)
+				os.Exit(1)
+			}
+
+			var failedWriter *failedRecordWriter
+			if failedOutPath != "" {
+				failedWriter, err = newFailedRecordWriter(failedOutPath)
+				if err != nil {
+					fmt.Printf("Error opening --failed-out file %s: %v\n", failedOutPath, err)
+					os.Exit(1)
+				}
+				defer failedWriter.Close()
+			}
+
+			fmt.Printf("Reading data from %s (format=%s)...\n", dataFile, format)
+
+			file, err := os.Open(dataFile)
 			if err != nil {
 				fmt.Printf("Error reading data file %s: %v\n", dataFile, err)
 				os.Exit(1)
 			}
+			defer file.Close()
+
+			records, sourceErrs := streamRawPageData(file, format, *database)
+
+			limiter := newRateLimiter(notionRequestsPerSecond)
+			var curlScript *curlScriptBuilder
+			if dryRun {
+				curlScript = &curlScriptBuilder{}
+			}
+
+			var (
+				mu                                      sync.Mutex
+				successCount, failCount, processedCount int
+			)
+			var wg sync.WaitGroup
+
+			batches := batchRecords(records, batchSize)
+			sem := make(chan struct{}, concurrency)
+
+			for batch := range batches {
+				batch := batch
+				sem <- struct{}{}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() { <-sem }()
+
+					for _, rec := range batch {
+						mu.Lock()
+						processedCount++
+						idx := processedCount
+						mu.Unlock()
+
+						if debugMode {
+							fmt.Printf("Processing record %d...\n", idx)
+						}
+
+						pageData, err := convertToNotionProperties(rec.data, *database)
+						if err != nil {
+							fmt.Printf("Error converting record %d: %v\n", idx, err)
+							mu.Lock()
+							failCount++
+							mu.Unlock()
+							if failedWriter != nil {
+								failedWriter.Write(rec.source, err)
+							}
+							continue
+						}
+
+						request := &notionapi.PageCreateRequest{
+							Parent:     notionapi.Parent{DatabaseID: notionapi.DatabaseID(dbID)},
+							Properties: pageData.Properties,
+						}
+
+						if debugMode {
+							requestJSON, _ := json.MarshalIndent(request, "", "  ")
+							fmt.Printf("\nRequest JSON:\n%s\n", string(requestJSON))
+						}
+
+						if dryRun {
+							curlScript.AddPageCreate(request)
+							fmt.Printf("Record %d: would insert (dry run).\n", idx)
+							mu.Lock()
+							successCount++
+							mu.Unlock()
+							continue
+						}
+
+						limiter.Wait(ctx)
+
+						var response *notionapi.Page
+						err = retryWithBackoff(ctx, maxInsertRetries, func() error {
+							var createErr error
+							response, createErr = client.Page.Create(ctx, request)
+							return createErr
+						})
+
+						if err != nil {
+							fmt.Printf("Record %d failed: %v\n", idx, err)
+							mu.Lock()
+							failCount++
+							mu.Unlock()
+							if failedWriter != nil {
+								failedWriter.Write(rec.source, err)
+							}
+							continue
+						}
+
+						fmt.Printf("Record %d inserted.", idx)
+						if response.URL != "" {
+							fmt.Printf(" Page URL: %s", response.URL)
+						}
+						fmt.Println()
+
+						mu.Lock()
+						successCount++
+						mu.Unlock()
+					}
+				}()
+			}
+
+			wg.Wait()
+
+			if err := <-sourceErrs; err != nil {
+				fmt.Printf("Error reading %s data: %v\n", format, err)
+			}
+
+			if dryRun {
+				if err := curlScript.WriteScript(dryRunOut, dbID); err != nil {
+					fmt.Printf("Error writing dry-run script: %v\n", err)
+					os.Exit(1)
+				}
+				destination := dryRunOut
+				if destination == "" {
+					destination = "stdout"
+				}
+				fmt.Printf("\nDry run complete. %d request(s) written to %s.\n", successCount, destination)
+				return
+			}
+
+			total := successCount + failCount
+			if successCount > 0 {
+				fmt.Printf("\nFinished inserting. %d/%d records inserted successfully.\n", successCount, total)
+				fmt.Println("\nTIP: If your data isn't visible in Notion:")
+				fmt.Println("1. Verify property names match exactly with database columns (case sensitive)")
+				fmt.Println("2. Run 'gotion inspect --db \"your-db-id\"' to see the database structure")
+				fmt.Println("3. Try running with --debug flag to see more details about the process")
+			} else {
+				fmt.Printf("\nFinished inserting. %d/%d records inserted successfully.\n", successCount, total)
+				fmt.Println("No records were successfully inserted. Check the errors above.")
+			}
+			if failedWriter != nil && failCount > 0 {
+				fmt.Printf("Details on the %d failed record(s) were written to %s.\n", failCount, failedOutPath)
+			}
+		},
+	}
+
+	// Add upsert command (aliased as "update") to sync records by key match
+	var upsertCmd = &cobra.Command{
+		Use:     "upsert",
+		Aliases: []string{"update"},
+		Short:   "Insert or update data from a JSON file, matching existing pages by key properties",
+		Long: `Insert or update data from a JSON file into a Notion database, using one or
+more properties to decide whether a page already exists.
+
+For each record, gotion queries the database for a page whose --key
+properties match the record's values. If a match is found, the existing
+page is updated, skipped, or counted as a failure, as --on-conflict says
+(update by default); otherwise a new page is created.
+
+--key only supports property types with a matching notionapi.PropertyFilter
+condition: rich text, number, select, and status. Title, URL, Email, and
+PhoneNumber properties have no such condition in this version of the
+library, and checkbox properties can't reliably express an "equals false"
+filter, so none of those types can be used as a key.
+
+Example: gotion upsert --db "f1a2b3c4-d5e6-7f8a-9b0c-1d2e3f4a5b6c" --data "data.json" --key SKU`,
+		Run: func(cmd *cobra.Command, args []string) {
+			dbID, _ := cmd.Flags().GetString("db")
+			dataFile, _ := cmd.Flags().GetString("data")
+			apiKeyFlag, _ := cmd.Flags().GetString("api-key")
+			debugMode, _ := cmd.Flags().GetBool("debug")
+			keyProps, _ := cmd.Flags().GetStringSlice("key")
+			onConflict, _ := cmd.Flags().GetString("on-conflict")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			dryRunOut, _ := cmd.Flags().GetString("dry-run-out")
+
+			// --- Input Validation ---
+			if dbID == "" || dataFile == "" {
+				fmt.Println("Error: Both --db (Database ID) and --data (JSON file path) flags are required.")
+				os.Exit(1)
+			}
+
+			if len(keyProps) == 0 {
+				fmt.Println("Error: --key is required and must name at least one property to match records on.")
+				os.Exit(1)
+			}
+
+			switch onConflict {
+			case "skip", "update", "error":
+			default:
+				fmt.Printf("Error: --on-conflict must be one of skip|update|error, got %q.\n", onConflict)
+				os.Exit(1)
+			}
+
+			// Validate UUID format for database ID
+			dbID = cleanDatabaseID(dbID)
+			if !isValidUUID(dbID) {
+				fmt.Println("Error: The database ID must be in UUID format.")
+				fmt.Println("Example: f1a2b3c4-d5e6-7f8a-9b0c-1d2e3f4a5b6c")
+				fmt.Println("You can find this in your Notion URL when viewing the database.")
+				os.Exit(1)
+			}
+
+			// --- Get API Key ---
+			apiKey := apiKeyFlag
+			if apiKey == "" {
+				apiKey = os.Getenv("NOTION_API_KEY")
+			}
+
+			if apiKey == "" {
+				fmt.Println("Error: Notion API key not provided. Set via --api-key flag or NOTION_API_KEY environment variable.")
+				os.Exit(1)
+			}
 
-			if debugMode {
-				fmt.Println("Raw JSON content:")
-				fmt.Println(string(content))
+			// --- Initialize Notion Client ---
+			client := notionapi.NewClient(notionapi.Token(apiKey))
+			ctx := context.Background()
+
+			// --- Read Data File ---
+			fmt.Printf("Reading data from %s...\n", dataFile)
+			content, err := os.ReadFile(dataFile)
+			if err != nil {
+				fmt.Printf("Error reading data file %s: %v\n", dataFile, err)
+				os.Exit(1)
 			}
 
-			// --- Parse Raw JSON Data First --- 
+			// --- Parse Raw JSON Data First ---
 			var rawData []RawPageData
 			err = json.Unmarshal(content, &rawData)
 			if err != nil {
-				// Try as single object if array fails
 				var singleRawData RawPageData
 				errSingle := json.Unmarshal(content, &singleRawData)
 				if errSingle != nil {
@@ -200,10 +471,7 @@ You can use the database ID with or without dashes. The tool will format it corr
 				rawData = []RawPageData{singleRawData}
 			}
 
-			fmt.Printf("Found %d record(s) to insert into database %s.\n", len(rawData), dbID)
-
-			// --- Convert Raw Data to Notion API Format --- 
-			pagesData := make([]PageData, 0, len(rawData))
+			fmt.Printf("Found %d record(s) to sync with database %s.\n", len(rawData), dbID)
 
 			// Fetch database schema
 			database, err := client.Database.Get(ctx, notionapi.DatabaseID(dbID))
@@ -212,241 +480,954 @@ You can use the database ID with or without dashes. The tool will format it corr
 				os.Exit(1)
 			}
 
+			for _, keyProp := range keyProps {
+				if _, exists := database.Properties[keyProp]; !exists {
+					fmt.Printf("Error: --key property %q does not exist in the database schema.\n", keyProp)
+					os.Exit(1)
+				}
+			}
+
+			var createdCount, updatedCount, skippedCount, failedCount int
+
+			var curlScript *curlScriptBuilder
+			if dryRun {
+				// The key-match query itself is read-only, so it still runs for real;
+				// only the resulting create/update request is replaced with a curl line.
+				curlScript = &curlScriptBuilder{}
+			}
+
 			for i, raw := range rawData {
 				if debugMode {
-					fmt.Printf("Processing record %d...\n", i + 1)
+					fmt.Printf("Processing record %d...\n", i+1)
 				}
 
-				// Convert raw data to Notion properties
 				pageData, err := convertToNotionProperties(raw, *database)
 				if err != nil {
-					fmt.Printf("Error converting record %d: %v\n", i + 1, err)
+					fmt.Printf("Error converting record %d: %v\n", i+1, err)
+					failedCount++
 					continue
 				}
 
-				pagesData = append(pagesData, pageData)
-			}
-
-			// --- Insert Data into Notion --- 
-			successCount := 0
-			for i, pageData := range pagesData {
-				fmt.Printf("Inserting record %d... ", i + 1)
-				
-				// Validate property names against database schema if debug mode is on
-				if debugMode {
-					// First check if we can fetch the database schema
-					database, err := client.Database.Get(ctx, notionapi.DatabaseID(dbID))
-					if err == nil {
-						// Check if the properties in our data match the database schema
-						for propName := range pageData.Properties {
-							if _, exists := database.Properties[propName]; !exists {
-								fmt.Printf("\nWARNING: Property '%s' does not exist in the database schema!\n", propName)
-								fmt.Printf("Available properties are: ")
-								for dbPropName := range database.Properties {
-									fmt.Printf("%s, ", dbPropName)
-								}
-								fmt.Println("\nEnsure your property names match exactly (including case).")
-							}
-						}
-					}
+				filter, err := buildKeyFilter(pageData.Properties, keyProps)
+				if err != nil {
+					fmt.Printf("Error building key filter for record %d: %v\n", i+1, err)
+					failedCount++
+					continue
 				}
-				
-				request := &notionapi.PageCreateRequest{
-					Parent: notionapi.Parent{
-						DatabaseID: notionapi.DatabaseID(dbID),
-					},
-					Properties: pageData.Properties,
+
+				queryResp, err := client.Database.Query(ctx, notionapi.DatabaseID(dbID), &notionapi.DatabaseQueryRequest{
+					Filter: filter,
+				})
+				if err != nil {
+					fmt.Printf("Error querying database for record %d: %v\n", i+1, err)
+					failedCount++
+					continue
 				}
 
-				if debugMode {
-					requestJSON, _ := json.MarshalIndent(request, "", "  ")
-					fmt.Printf("\nRequest JSON:\n%s\n", string(requestJSON))
+				if len(queryResp.Results) == 0 {
+					createReq := &notionapi.PageCreateRequest{
+						Parent:     notionapi.Parent{DatabaseID: notionapi.DatabaseID(dbID)},
+						Properties: pageData.Properties,
+					}
+
+					if dryRun {
+						curlScript.AddPageCreate(createReq)
+						fmt.Printf("Record %d: no match found, would create (dry run).\n", i+1)
+						createdCount++
+						continue
+					}
+
+					fmt.Printf("Record %d: no match found, creating... ", i+1)
+					_, err := client.Page.Create(ctx, createReq)
+					if err != nil {
+						fmt.Printf("Failed: %v\n", err)
+						failedCount++
+						continue
+					}
+					fmt.Println("Created!")
+					createdCount++
+					continue
 				}
 
-				response, err := client.Page.Create(ctx, request)
-				if err != nil {
-					fmt.Printf("Failed: %v\n", err)
-					
-					// Check for common permission errors
-					if strings.Contains(err.Error(), "Could not find database") {
-						fmt.Println("\nPermission Error: Your integration doesn't have access to this database.")
-						fmt.Println("To fix this:")
-						fmt.Println("1. Go to your database in Notion")
-						fmt.Println("2. Click the \"...\" menu in the top right corner")
-						fmt.Println("3. Select \"Add connections\"")
-						fmt.Println("4. Find and select your integration name")
-						fmt.Println("\nAlso verify that your Database ID is correct.")
-						
-						// Only show this detailed help for the first error
-						if i == 0 {
-							fmt.Println("\nFor more help, visit: https://developers.notion.com/docs/getting-started")
-						}
+				matchedPage := queryResp.Results[0]
+				switch onConflict {
+				case "skip":
+					fmt.Printf("Record %d: match found (%s), skipping.\n", i+1, matchedPage.ID)
+					skippedCount++
+				case "error":
+					fmt.Printf("Record %d: match found (%s), treating as error per --on-conflict=error.\n", i+1, matchedPage.ID)
+					failedCount++
+				case "update":
+					updateReq := &notionapi.PageUpdateRequest{Properties: pageData.Properties}
+
+					if dryRun {
+						curlScript.AddPageUpdate(notionapi.PageID(matchedPage.ID), updateReq)
+						fmt.Printf("Record %d: match found (%s), would update (dry run).\n", i+1, matchedPage.ID)
+						updatedCount++
+						continue
 					}
-				} else {
-					fmt.Println("Success!")
-					successCount++
-					
-					// Print URL of created page if available
-					if response.URL != "" {
-						fmt.Printf("Page URL: %s\n", response.URL)
+
+					fmt.Printf("Record %d: match found (%s), updating... ", i+1, matchedPage.ID)
+					_, err := client.Page.Update(ctx, notionapi.PageID(matchedPage.ID), updateReq)
+					if err != nil {
+						fmt.Printf("Failed: %v\n", err)
+						failedCount++
+						continue
 					}
+					fmt.Println("Updated!")
+					updatedCount++
 				}
 			}
-			
-			if successCount > 0 {
-				fmt.Printf("\nFinished inserting. %d/%d records inserted successfully.\n", successCount, len(pagesData))
-				fmt.Println("\nTIP: If your data isn't visible in Notion:")
-				fmt.Println("1. Verify property names match exactly with database columns (case sensitive)")
-				fmt.Println("2. Run 'gotion inspect --db \"your-db-id\"' to see the database structure")
-				fmt.Println("3. Try running with --debug flag to see more details about the process")
-			} else {
-				fmt.Printf("\nFinished inserting. %d/%d records inserted successfully.\n", successCount, len(pagesData))
-				fmt.Println("No records were successfully inserted. Check the errors above.")
+
+			if dryRun {
+				if err := curlScript.WriteScript(dryRunOut, dbID); err != nil {
+					fmt.Printf("Error writing dry-run script: %v\n", err)
+					os.Exit(1)
+				}
+				destination := dryRunOut
+				if destination == "" {
+					destination = "stdout"
+				}
+				fmt.Printf("\nDry run complete. %d request(s) written to %s.\n", createdCount+updatedCount, destination)
+				return
 			}
+
+			fmt.Printf("\nFinished syncing. %d created, %d updated, %d skipped, %d failed out of %d record(s).\n",
+				createdCount, updatedCount, skippedCount, failedCount, len(rawData))
 		},
 	}
 
-	insertCmd.Flags().String("db", "", "ID of the Notion database")
-	insertCmd.Flags().String("data", "", "Path to the JSON file")
-	insertCmd.Flags().String("api-key", "", "Notion API Key (optional, overrides NOTION_API_KEY env var)")
-	insertCmd.Flags().Bool("debug", false, "Enable debug mode for verbose output")
-	insertCmd.MarkFlagRequired("db")
-	insertCmd.MarkFlagRequired("data")
-
-	// Add common flags
-	inspectCmd.Flags().String("db", "", "ID of the Notion database")
-	inspectCmd.Flags().String("api-key", "", "Notion API Key (optional, overrides NOTION_API_KEY env var)")
-	inspectCmd.MarkFlagRequired("db")
-	
-	// Add commands to root
-	rootCmd.AddCommand(inspectCmd)
-	rootCmd.AddCommand(insertCmd)
+	// Add export command to pull a database back out to a file
+	var exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export a Notion database to JSON, CSV, or Markdown",
+		Long: `Export a Notion database to a file, paging through every record.
 
-	rootCmd.Execute()
-}
+JSON and CSV output use the same shorthand property shape that "insert"
+accepts, so a round trip of "gotion export ... --format json" followed by
+"gotion insert" reproduces the data. Markdown output additionally fetches
+each page's content blocks and renders headings, paragraphs, lists, code,
+and to-do blocks.
 
-// isValidUUID checks if the input string is a valid UUID
-func isValidUUID(uuid string) bool {
-	r := regexp.MustCompile("^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12}$")
-	return r.MatchString(uuid)
-}
+Example: gotion export --db "f1a2b3c4-d5e6-7f8a-9b0c-1d2e3f4a5b6c" --out data.json --format json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			dbID, _ := cmd.Flags().GetString("db")
+			outPath, _ := cmd.Flags().GetString("out")
+			format, _ := cmd.Flags().GetString("format")
+			apiKeyFlag, _ := cmd.Flags().GetString("api-key")
+			filterPath, _ := cmd.Flags().GetString("filter")
+			sortJSON, _ := cmd.Flags().GetString("sort")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			dryRunOut, _ := cmd.Flags().GetString("dry-run-out")
 
-// cleanDatabaseID attempts to extract a UUID from various formats
-// (like URLs or when dashes are missing)
-func cleanDatabaseID(input string) string {
-	// If it contains a dash already, it might be a proper UUID format
-	if strings.Contains(input, "-") {
-		return input
-	}
-	
-	// Check if it's a 32-character hex string without dashes
-	r := regexp.MustCompile("^[a-fA-F0-9]{32}$")
-	if r.MatchString(input) {
-		// Insert dashes in the UUID format positions
-		return fmt.Sprintf("%s-%s-%s-%s-%s",
-			input[0:8],
-			input[8:12],
-			input[12:16],
-			input[16:20],
-			input[20:32])
-	}
-	
-	// Extract ID from URL if it appears to be a Notion URL
-	if strings.Contains(input, "notion.so") {
-		parts := strings.Split(input, "/")
-		lastPart := parts[len(parts)-1]
-		// Check if the last part might be an ID
-		if len(lastPart) >= 32 {
-			// Try to clean this last part
-			return cleanDatabaseID(lastPart)
-		}
-	}
-	
-	// Return as is if we can't determine a better format
-	return input
-}
+			// --- Input Validation ---
+			if dbID == "" || outPath == "" {
+				fmt.Println("Error: Both --db (Database ID) and --out (output file path) flags are required.")
+				os.Exit(1)
+			}
 
-// Dynamically handle all property types based on the database schema
-func convertToNotionProperties(raw RawPageData, schema notionapi.Database) (PageData, error) {
-    var result PageData
-    result.Properties = make(notionapi.Properties)
-
-    // Check if raw has a "properties" key
-    if props, ok := raw["properties"].(map[string]interface{}); ok {
-        for propName, propValue := range props {
-            schemaProp, exists := schema.Properties[propName]
-            if !exists {
-                continue // Skip properties not in the database schema
-            }
-            var notionProp notionapi.Property
-            switch schemaProp.GetType() {
-            case notionapi.PropertyConfigTypeTitle:
-                // If needed, you can pass the value as-is or transform further
-                if strValue, ok := propValue.(string); ok {
-                    notionProp = &notionapi.TitleProperty{
-                        Title: []notionapi.RichText{
-                            {
-                                Text: &notionapi.Text{
-                                    Content: strValue,
-                                },
-                            },
-                        },
-                    }
-                }
-            case notionapi.PropertyConfigTypeRichText:
-                if strValue, ok := propValue.(string); ok {
-                    notionProp = &notionapi.RichTextProperty{
-                        RichText: []notionapi.RichText{
-                            {
-                                Text: &notionapi.Text{
-                                    Content: strValue,
-                                },
-                            },
-                        },
-                    }
-                }
-            case notionapi.PropertyConfigTypeNumber:
-                if numValue, ok := propValue.(float64); ok {
-                    notionProp = &notionapi.NumberProperty{
-                        Number: numValue,
-                    }
-                }
-            default:
-                continue // Skip unsupported property types
-            }
-            
-            if notionProp != nil {
-                result.Properties[propName] = notionProp
-            }
-        }
-    } else {
-        // Optionally handle the case where raw is not structured with a "properties" key.
-        return result, fmt.Errorf("expected key 'properties' in data, got none")
-    }
-    
-    return result, nil
-}
+			switch format {
+			case "json", "csv", "md":
+			default:
+				fmt.Printf("Error: --format must be one of json|csv|md, got %q.\n", format)
+				os.Exit(1)
+			}
 
-// Helper functions for database inspection
-func getTitle(titleArray []notionapi.RichText) string {
-	if len(titleArray) == 0 {
-		return "Untitled"
-	}
-	
-	var title string
-	for _, text := range titleArray {
-		if text.Text != nil {
-			title += text.Text.Content
-		}
-	}
-	
-	return title
-}
+			dbID = cleanDatabaseID(dbID)
+			if !isValidUUID(dbID) {
+				fmt.Println("Error: The database ID must be in UUID format.")
+				fmt.Println("Example: f1a2b3c4-d5e6-7f8a-9b0c-1d2e3f4a5b6c")
+				fmt.Println("You can find this in your Notion URL when viewing the database.")
+				os.Exit(1)
+			}
 
-func getPropertyTypeString(prop notionapi.PropertyConfig) string {
-	switch prop.GetType() {
-	case notionapi.PropertyConfigTypeTitle:
-		return "Title"
+			apiKey := apiKeyFlag
+			if apiKey == "" {
+				apiKey = os.Getenv("NOTION_API_KEY")
+			}
+			if apiKey == "" {
+				fmt.Println("Error: Notion API key not provided. Set via --api-key flag or NOTION_API_KEY environment variable.")
+				os.Exit(1)
+			}
+
+			var queryFilter notionapi.Filter
+			if filterPath != "" {
+				filterContent, err := os.ReadFile(filterPath)
+				if err != nil {
+					fmt.Printf("Error reading --filter file %s: %v\n", filterPath, err)
+					os.Exit(1)
+				}
+				queryFilter, err = parseQueryFilter(filterContent)
+				if err != nil {
+					fmt.Printf("Error parsing --filter file %s: %v\n", filterPath, err)
+					os.Exit(1)
+				}
+			}
+
+			var sorts []notionapi.SortObject
+			if sortJSON != "" {
+				if err := json.Unmarshal([]byte(sortJSON), &sorts); err != nil {
+					fmt.Printf("Error parsing --sort as JSON: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
+			client := notionapi.NewClient(notionapi.Token(apiKey))
+			ctx := context.Background()
+
+			if dryRun {
+				// Only the initial query is reproducible: later pages depend on the
+				// start_cursor Notion returns at run time, which a dry run never sees.
+				curlScript := &curlScriptBuilder{}
+				curlScript.AddDatabaseQuery(notionapi.DatabaseID(dbID), &notionapi.DatabaseQueryRequest{
+					Filter:      queryFilter,
+					Sorts:       sorts,
+					StartCursor: notionapi.Cursor(""),
+					PageSize:    100,
+				})
+				if err := curlScript.WriteScript(dryRunOut, dbID); err != nil {
+					fmt.Printf("Error writing dry-run script: %v\n", err)
+					os.Exit(1)
+				}
+				destination := dryRunOut
+				if destination == "" {
+					destination = "stdout"
+				}
+				fmt.Printf("Dry run complete. The first page request was written to %s.\n", destination)
+				fmt.Println("Note: subsequent pages depend on the live start_cursor, so only the first request could be reproduced.")
+				return
+			}
+
+			out, err := os.Create(outPath)
+			if err != nil {
+				fmt.Printf("Error creating output file %s: %v\n", outPath, err)
+				os.Exit(1)
+			}
+			defer out.Close()
+
+			fmt.Printf("Querying database %s...\n", dbID)
+
+			recordCount := 0
+			var csvHeader []string
+			var csvWriter *csv.Writer
+			if format == "csv" {
+				csvWriter = csv.NewWriter(out)
+				defer csvWriter.Flush()
+			}
+			if format == "json" {
+				fmt.Fprint(out, "[\n")
+			}
+
+			cursor := notionapi.Cursor("")
+			for {
+				queryReq := &notionapi.DatabaseQueryRequest{
+					Filter:      queryFilter,
+					Sorts:       sorts,
+					StartCursor: cursor,
+					PageSize:    100,
+				}
+
+				resp, err := client.Database.Query(ctx, notionapi.DatabaseID(dbID), queryReq)
+				if err != nil {
+					fmt.Printf("Error querying database: %v\n", err)
+					os.Exit(1)
+				}
+
+				for _, page := range resp.Results {
+					shorthand, err := pagePropertiesToShorthand(page.Properties)
+					if err != nil {
+						fmt.Printf("Error exporting page %s: %v\n", page.ID, err)
+						continue
+					}
+
+					switch format {
+					case "json":
+						if recordCount > 0 {
+							fmt.Fprint(out, ",\n")
+						}
+						recordJSON, _ := json.MarshalIndent(RawPageData{"properties": shorthand}, "  ", "  ")
+						fmt.Fprint(out, "  ")
+						fmt.Fprint(out, string(recordJSON))
+					case "csv":
+						if csvHeader == nil {
+							csvHeader = csvHeaderFromShorthand(shorthand)
+							csvWriter.Write(csvHeader)
+						}
+						row := make([]string, len(csvHeader))
+						for i, col := range csvHeader {
+							row[i] = csvCellFromShorthand(shorthand[col])
+						}
+						csvWriter.Write(row)
+					case "md":
+						blocks, err := fetchAllChildren(ctx, client, notionapi.BlockID(page.ID))
+						if err != nil {
+							fmt.Printf("Error fetching blocks for page %s: %v\n", page.ID, err)
+						}
+						fmt.Fprint(out, renderPageMarkdown(page, blocks))
+						fmt.Fprint(out, "\n---\n\n")
+					}
+
+					recordCount++
+				}
+
+				if !resp.HasMore {
+					break
+				}
+				cursor = resp.NextCursor
+			}
+
+			if format == "json" {
+				fmt.Fprint(out, "\n]\n")
+			}
+
+			fmt.Printf("Exported %d record(s) to %s.\n", recordCount, outPath)
+		},
+	}
+
+	// Add init command to scaffold a JSON template from a database's schema
+	var initCmd = &cobra.Command{
+		Use:   "init",
+		Short: "Scaffold a JSON template from a database's schema",
+		Long: `Fetch a database's schema and write a fully-populated example JSON
+document, so you have a starting point that already has the right shape
+for every property in the database.
+
+Example: gotion init --db "f1a2b3c4-d5e6-7f8a-9b0c-1d2e3f4a5b6c" --out template.json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			dbID, _ := cmd.Flags().GetString("db")
+			outPath, _ := cmd.Flags().GetString("out")
+			apiKeyFlag, _ := cmd.Flags().GetString("api-key")
+
+			if dbID == "" || outPath == "" {
+				fmt.Println("Error: Both --db (Database ID) and --out (template file path) flags are required.")
+				os.Exit(1)
+			}
+
+			dbID = cleanDatabaseID(dbID)
+			if !isValidUUID(dbID) {
+				fmt.Println("Error: The database ID must be in UUID format.")
+				fmt.Println("Example: f1a2b3c4-d5e6-7f8a-9b0c-1d2e3f4a5b6c")
+				fmt.Println("You can find this in your Notion URL when viewing the database.")
+				os.Exit(1)
+			}
+
+			apiKey := apiKeyFlag
+			if apiKey == "" {
+				apiKey = os.Getenv("NOTION_API_KEY")
+			}
+			if apiKey == "" {
+				fmt.Println("Error: Notion API key not provided. Set via --api-key flag or NOTION_API_KEY environment variable.")
+				os.Exit(1)
+			}
+
+			client := notionapi.NewClient(notionapi.Token(apiKey))
+			ctx := context.Background()
+
+			fmt.Printf("Fetching schema for database %s...\n", dbID)
+			database, err := client.Database.Get(ctx, notionapi.DatabaseID(dbID))
+			if err != nil {
+				fmt.Printf("Error accessing database: %v\n", err)
+				os.Exit(1)
+			}
+
+			properties := make(map[string]interface{}, len(database.Properties))
+			for name, prop := range database.Properties {
+				properties[name] = sampleShorthandForProperty(prop)
+			}
+
+			template := []RawPageData{{"properties": properties}}
+			templateJSON, err := json.MarshalIndent(template, "", "  ")
+			if err != nil {
+				fmt.Printf("Error building template: %v\n", err)
+				os.Exit(1)
+			}
+
+			if err := os.WriteFile(outPath, templateJSON, 0644); err != nil {
+				fmt.Printf("Error writing template to %s: %v\n", outPath, err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Wrote a template with %d propert%s to %s.\n", len(properties), pluralSuffix(len(properties)), outPath)
+		},
+	}
+
+	// Add validate command to check data against a schema without writing
+	var validateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Check a JSON data file against a database's schema without writing",
+		Long: `Run the same conversion path as "insert" against a database's schema,
+but stop before any network write. Every mismatch in the file is reported
+in one pass: unknown properties, values that don't coerce to the expected
+type, and select/multi-select/status values that aren't one of the
+database's configured options.
+
+Example: gotion validate --db "f1a2b3c4-d5e6-7f8a-9b0c-1d2e3f4a5b6c" --data data.json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			dbID, _ := cmd.Flags().GetString("db")
+			dataFile, _ := cmd.Flags().GetString("data")
+			apiKeyFlag, _ := cmd.Flags().GetString("api-key")
+
+			if dbID == "" || dataFile == "" {
+				fmt.Println("Error: Both --db (Database ID) and --data (JSON file path) flags are required.")
+				os.Exit(1)
+			}
+
+			dbID = cleanDatabaseID(dbID)
+			if !isValidUUID(dbID) {
+				fmt.Println("Error: The database ID must be in UUID format.")
+				fmt.Println("Example: f1a2b3c4-d5e6-7f8a-9b0c-1d2e3f4a5b6c")
+				fmt.Println("You can find this in your Notion URL when viewing the database.")
+				os.Exit(1)
+			}
+
+			apiKey := apiKeyFlag
+			if apiKey == "" {
+				apiKey = os.Getenv("NOTION_API_KEY")
+			}
+			if apiKey == "" {
+				fmt.Println("Error: Notion API key not provided. Set via --api-key flag or NOTION_API_KEY environment variable.")
+				os.Exit(1)
+			}
+
+			client := notionapi.NewClient(notionapi.Token(apiKey))
+			ctx := context.Background()
+
+			content, err := os.ReadFile(dataFile)
+			if err != nil {
+				fmt.Printf("Error reading data file %s: %v\n", dataFile, err)
+				os.Exit(1)
+			}
+
+			var rawData []RawPageData
+			if err := json.Unmarshal(content, &rawData); err != nil {
+				var single RawPageData
+				if errSingle := json.Unmarshal(content, &single); errSingle != nil {
+					fmt.Printf("Error parsing JSON data: %v\n", err)
+					os.Exit(1)
+				}
+				rawData = []RawPageData{single}
+			}
+
+			database, err := client.Database.Get(ctx, notionapi.DatabaseID(dbID))
+			if err != nil {
+				fmt.Printf("Error accessing database: %v\n", err)
+				os.Exit(1)
+			}
+
+			invalidCount := 0
+			for i, raw := range rawData {
+				if errs := validatePageData(raw, *database); len(errs) > 0 {
+					invalidCount++
+					fmt.Printf("Record %d: %v\n", i+1, errs)
+				}
+			}
+
+			if invalidCount == 0 {
+				fmt.Printf("All %d record(s) are valid against the schema for database %s.\n", len(rawData), dbID)
+				return
+			}
+
+			fmt.Printf("\n%d/%d record(s) failed validation. Fix the issues above before running insert.\n", invalidCount, len(rawData))
+			os.Exit(1)
+		},
+	}
+
+	insertCmd.Flags().String("db", "", "ID of the Notion database")
+	insertCmd.Flags().String("data", "", "Path to the data file (json, csv or ndjson; see --format)")
+	insertCmd.Flags().String("api-key", "", "Notion API Key (optional, overrides NOTION_API_KEY env var)")
+	insertCmd.Flags().Bool("debug", false, "Enable debug mode for verbose output")
+	insertCmd.Flags().String("format", "json", "Input file format: json|csv|ndjson")
+	insertCmd.Flags().Int("concurrency", 1, "Number of records to insert concurrently")
+	insertCmd.Flags().Int("batch-size", 1, "Number of records each worker processes per dispatch")
+	insertCmd.Flags().String("failed-out", "", "Path to write failed records plus their errors, for resuming a run")
+	insertCmd.Flags().Bool("dry-run", false, "Write an equivalent curl script instead of calling Notion")
+	insertCmd.Flags().String("dry-run-out", "", "Path to write the --dry-run curl script to (default: stdout)")
+	insertCmd.MarkFlagRequired("db")
+	insertCmd.MarkFlagRequired("data")
+
+	upsertCmd.Flags().String("db", "", "ID of the Notion database")
+	upsertCmd.Flags().String("data", "", "Path to the JSON file")
+	upsertCmd.Flags().String("api-key", "", "Notion API Key (optional, overrides NOTION_API_KEY env var)")
+	upsertCmd.Flags().Bool("debug", false, "Enable debug mode for verbose output")
+	upsertCmd.Flags().StringSlice("key", nil, "Database propert(y/ies) to match existing pages on (repeatable or comma-separated); rich text, number, select, and status only")
+	upsertCmd.Flags().String("on-conflict", "update", "What to do when a matching page is found: skip|update|error")
+	upsertCmd.Flags().Bool("dry-run", false, "Write an equivalent curl script instead of calling Notion")
+	upsertCmd.Flags().String("dry-run-out", "", "Path to write the --dry-run curl script to (default: stdout)")
+	upsertCmd.MarkFlagRequired("db")
+	upsertCmd.MarkFlagRequired("data")
+	upsertCmd.MarkFlagRequired("key")
+
+	exportCmd.Flags().String("db", "", "ID of the Notion database")
+	exportCmd.Flags().String("out", "", "Path to write the exported data to")
+	exportCmd.Flags().String("format", "json", "Output format: json|csv|md")
+	exportCmd.Flags().String("api-key", "", "Notion API Key (optional, overrides NOTION_API_KEY env var)")
+	exportCmd.Flags().String("filter", "", "Path to a JSON file containing a single Notion property filter condition (no and/or compound filters)")
+	exportCmd.Flags().String("sort", "", "A JSON array of Notion sort objects")
+	exportCmd.Flags().Bool("dry-run", false, "Write an equivalent curl script instead of calling Notion")
+	exportCmd.Flags().String("dry-run-out", "", "Path to write the --dry-run curl script to (default: stdout)")
+	exportCmd.MarkFlagRequired("db")
+	exportCmd.MarkFlagRequired("out")
+
+	initCmd.Flags().String("db", "", "ID of the Notion database")
+	initCmd.Flags().String("out", "", "Path to write the generated template to")
+	initCmd.Flags().String("api-key", "", "Notion API Key (optional, overrides NOTION_API_KEY env var)")
+	initCmd.MarkFlagRequired("db")
+	initCmd.MarkFlagRequired("out")
+
+	validateCmd.Flags().String("db", "", "ID of the Notion database")
+	validateCmd.Flags().String("data", "", "Path to the JSON file")
+	validateCmd.Flags().String("api-key", "", "Notion API Key (optional, overrides NOTION_API_KEY env var)")
+	validateCmd.MarkFlagRequired("db")
+	validateCmd.MarkFlagRequired("data")
+
+	// Add common flags
+	inspectCmd.Flags().String("db", "", "ID of the Notion database")
+	inspectCmd.Flags().String("api-key", "", "Notion API Key (optional, overrides NOTION_API_KEY env var)")
+	inspectCmd.MarkFlagRequired("db")
+
+	// Add commands to root
+	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(insertCmd)
+	rootCmd.AddCommand(upsertCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(validateCmd)
+
+	rootCmd.Execute()
+}
+
+// isValidUUID checks if the input string is a valid UUID
+func isValidUUID(uuid string) bool {
+	r := regexp.MustCompile("^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12}$")
+	return r.MatchString(uuid)
+}
+
+// cleanDatabaseID attempts to extract a UUID from various formats
+// (like URLs or when dashes are missing)
+func cleanDatabaseID(input string) string {
+	// If it contains a dash already, it might be a proper UUID format
+	if strings.Contains(input, "-") {
+		return input
+	}
+	
+	// Check if it's a 32-character hex string without dashes
+	r := regexp.MustCompile("^[a-fA-F0-9]{32}$")
+	if r.MatchString(input) {
+		// Insert dashes in the UUID format positions
+		return fmt.Sprintf("%s-%s-%s-%s-%s",
+			input[0:8],
+			input[8:12],
+			input[12:16],
+			input[16:20],
+			input[20:32])
+	}
+	
+	// Extract ID from URL if it appears to be a Notion URL
+	if strings.Contains(input, "notion.so") {
+		parts := strings.Split(input, "/")
+		lastPart := parts[len(parts)-1]
+		// Check if the last part might be an ID
+		if len(lastPart) >= 32 {
+			// Try to clean this last part
+			return cleanDatabaseID(lastPart)
+		}
+	}
+	
+	// Return as is if we can't determine a better format
+	return input
+}
+
+// PropertyConversionError describes a single field that failed to coerce
+// into a Notion property during convertToNotionProperties.
+type PropertyConversionError struct {
+	Property string
+	Err      error
+}
+
+func (e *PropertyConversionError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Property, e.Err)
+}
+
+func (e *PropertyConversionError) Unwrap() error {
+	return e.Err
+}
+
+// ConversionErrors collects every PropertyConversionError found while
+// converting a single record, so callers can report all problems at once
+// instead of bailing on the first bad field.
+type ConversionErrors []*PropertyConversionError
+
+func (e ConversionErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fieldErr := range e {
+		msgs[i] = fieldErr.Error()
+	}
+	return fmt.Sprintf("%d propert%s failed to convert: %s", len(e), pluralSuffix(len(e)), strings.Join(msgs, "; "))
+}
+
+func pluralSuffix(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
+
+// Dynamically handle all property types based on the database schema
+func convertToNotionProperties(raw RawPageData, schema notionapi.Database) (PageData, error) {
+	var result PageData
+	result.Properties = make(notionapi.Properties)
+
+	// Check if raw has a "properties" key
+	props, ok := raw["properties"].(map[string]interface{})
+	if !ok {
+		// Optionally handle the case where raw is not structured with a "properties" key.
+		return result, fmt.Errorf("expected key 'properties' in data, got none")
+	}
+
+	var errs ConversionErrors
+	for propName, propValue := range props {
+		schemaProp, exists := schema.Properties[propName]
+		if !exists {
+			continue // Skip properties not in the database schema
+		}
+
+		notionProp, err := convertPropertyValue(schemaProp.GetType(), propValue)
+		if err != nil {
+			errs = append(errs, &PropertyConversionError{Property: propName, Err: err})
+			continue
+		}
+		if notionProp != nil {
+			result.Properties[propName] = notionProp
+		}
+	}
+
+	if len(errs) > 0 {
+		return result, errs
+	}
+
+	return result, nil
+}
+
+// convertPropertyValue coerces a single raw JSON value into the notionapi.Property
+// matching propType. It accepts both the raw Notion-shaped JSON (the same shape
+// the Notion API itself returns for that property type) and a handful of
+// convenient shorthand scalar/array forms.
+func convertPropertyValue(propType notionapi.PropertyConfigType, value interface{}) (notionapi.Property, error) {
+	switch propType {
+	case notionapi.PropertyConfigTypeTitle:
+		if strValue, ok := value.(string); ok {
+			return &notionapi.TitleProperty{
+				Title: []notionapi.RichText{{Text: &notionapi.Text{Content: strValue}}},
+			}, nil
+		}
+		var p notionapi.TitleProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected a string or a title object: %w", err)
+		}
+		return &p, nil
+
+	case notionapi.PropertyConfigTypeRichText:
+		if strValue, ok := value.(string); ok {
+			return &notionapi.RichTextProperty{
+				RichText: []notionapi.RichText{{Text: &notionapi.Text{Content: strValue}}},
+			}, nil
+		}
+		var p notionapi.RichTextProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected a string or a rich_text object: %w", err)
+		}
+		return &p, nil
+
+	case notionapi.PropertyConfigTypeNumber:
+		if numValue, ok := value.(float64); ok {
+			return &notionapi.NumberProperty{Number: numValue}, nil
+		}
+		var p notionapi.NumberProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected a number or a number object: %w", err)
+		}
+		return &p, nil
+
+	case notionapi.PropertyConfigTypeSelect:
+		if strValue, ok := value.(string); ok {
+			return &notionapi.SelectProperty{Select: notionapi.Option{Name: strValue}}, nil
+		}
+		var p notionapi.SelectProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected a string or a select object: %w", err)
+		}
+		return &p, nil
+
+	case notionapi.PropertyConfigStatus:
+		if strValue, ok := value.(string); ok {
+			return &notionapi.StatusProperty{Status: notionapi.Option{Name: strValue}}, nil
+		}
+		var p notionapi.StatusProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected a string or a status object: %w", err)
+		}
+		return &p, nil
+
+	case notionapi.PropertyConfigTypeMultiSelect:
+		if arr, ok := value.([]interface{}); ok {
+			options := make([]notionapi.Option, 0, len(arr))
+			for _, item := range arr {
+				strItem, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected multi_select entries to be strings, got %T", item)
+				}
+				options = append(options, notionapi.Option{Name: strItem})
+			}
+			return &notionapi.MultiSelectProperty{MultiSelect: options}, nil
+		}
+		var p notionapi.MultiSelectProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected an array of strings or a multi_select object: %w", err)
+		}
+		return &p, nil
+
+	case notionapi.PropertyConfigTypeDate:
+		if strValue, ok := value.(string); ok {
+			start, err := parseNotionDate(strValue)
+			if err != nil {
+				return nil, fmt.Errorf("expected an ISO-8601 date: %w", err)
+			}
+			return &notionapi.DateProperty{Date: &notionapi.DateObject{Start: start}}, nil
+		}
+		if m, ok := value.(map[string]interface{}); ok {
+			if _, isRaw := m["date"]; !isRaw {
+				// Shorthand {start, end} object.
+				var shorthand struct {
+					Start string  `json:"start"`
+					End   *string `json:"end"`
+				}
+				if err := decodeNotionShape(value, &shorthand); err != nil {
+					return nil, fmt.Errorf("expected a {start, end} date object: %w", err)
+				}
+				start, err := parseNotionDate(shorthand.Start)
+				if err != nil {
+					return nil, fmt.Errorf("invalid start date: %w", err)
+				}
+				dateObj := &notionapi.DateObject{Start: start}
+				if shorthand.End != nil {
+					end, err := parseNotionDate(*shorthand.End)
+					if err != nil {
+						return nil, fmt.Errorf("invalid end date: %w", err)
+					}
+					dateObj.End = end
+				}
+				return &notionapi.DateProperty{Date: dateObj}, nil
+			}
+		}
+		var p notionapi.DateProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected an ISO-8601 date, a {start, end} object, or a date object: %w", err)
+		}
+		return &p, nil
+
+	case notionapi.PropertyConfigTypePeople:
+		if arr, ok := value.([]interface{}); ok {
+			people := make([]notionapi.User, 0, len(arr))
+			for _, item := range arr {
+				strItem, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected people entries to be user ID strings, got %T", item)
+				}
+				people = append(people, notionapi.User{ID: notionapi.UserID(strItem)})
+			}
+			return &notionapi.PeopleProperty{People: people}, nil
+		}
+		var p notionapi.PeopleProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected an array of user IDs or a people object: %w", err)
+		}
+		return &p, nil
+
+	case notionapi.PropertyConfigTypeFiles:
+		if arr, ok := value.([]interface{}); ok {
+			files := make([]notionapi.File, 0, len(arr))
+			for _, item := range arr {
+				strItem, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected files entries to be URL strings, got %T", item)
+				}
+				files = append(files, notionapi.File{
+					Name:     strItem,
+					Type:     "external",
+					External: &notionapi.FileObject{URL: strItem},
+				})
+			}
+			return &notionapi.FilesProperty{Files: files}, nil
+		}
+		var p notionapi.FilesProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected an array of URLs or a files object: %w", err)
+		}
+		return &p, nil
+
+	case notionapi.PropertyConfigTypeCheckbox:
+		if boolValue, ok := value.(bool); ok {
+			return &notionapi.CheckboxProperty{Checkbox: boolValue}, nil
+		}
+		var p notionapi.CheckboxProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected a bool or a checkbox object: %w", err)
+		}
+		return &p, nil
+
+	case notionapi.PropertyConfigTypeURL:
+		if strValue, ok := value.(string); ok {
+			return &notionapi.URLProperty{URL: strValue}, nil
+		}
+		var p notionapi.URLProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected a string or a url object: %w", err)
+		}
+		return &p, nil
+
+	case notionapi.PropertyConfigTypeEmail:
+		if strValue, ok := value.(string); ok {
+			return &notionapi.EmailProperty{Email: strValue}, nil
+		}
+		var p notionapi.EmailProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected a string or an email object: %w", err)
+		}
+		return &p, nil
+
+	case notionapi.PropertyConfigTypePhoneNumber:
+		if strValue, ok := value.(string); ok {
+			return &notionapi.PhoneNumberProperty{PhoneNumber: strValue}, nil
+		}
+		var p notionapi.PhoneNumberProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected a string or a phone_number object: %w", err)
+		}
+		return &p, nil
+
+	case notionapi.PropertyConfigTypeRelation:
+		if arr, ok := value.([]interface{}); ok {
+			relations := make([]notionapi.Relation, 0, len(arr))
+			for _, item := range arr {
+				strItem, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected relation entries to be page ID strings, got %T", item)
+				}
+				relations = append(relations, notionapi.Relation{ID: notionapi.PageID(strItem)})
+			}
+			return &notionapi.RelationProperty{Relation: relations}, nil
+		}
+		var p notionapi.RelationProperty
+		if err := decodeNotionShape(value, &p); err != nil {
+			return nil, fmt.Errorf("expected an array of page IDs or a relation object: %w", err)
+		}
+		return &p, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported property type %q", propType)
+	}
+}
+
+// buildKeyFilter builds a Database.Query filter that matches pages whose key
+// properties equal the values already converted onto props. When more than
+// one key property is given, the filter requires all of them to match.
+func buildKeyFilter(props notionapi.Properties, keyProps []string) (notionapi.Filter, error) {
+	conditions := make([]notionapi.Filter, 0, len(keyProps))
+
+	for _, keyProp := range keyProps {
+		prop, ok := props[keyProp]
+		if !ok {
+			return nil, fmt.Errorf("key property %q was not present or failed to convert on this record", keyProp)
+		}
+
+		condition, err := equalsFilter(keyProp, prop)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	if len(conditions) == 1 {
+		return conditions[0], nil
+	}
+
+	return notionapi.AndCompoundFilter(conditions), nil
+}
+
+// equalsFilter builds a PropertyFilter that matches prop's current value
+// exactly, for use as one leg of an upsert key match.
+func equalsFilter(propName string, prop notionapi.Property) (notionapi.Filter, error) {
+	switch p := prop.(type) {
+	case *notionapi.RichTextProperty:
+		return &notionapi.PropertyFilter{Property: propName, RichText: &notionapi.TextFilterCondition{Equals: getTitle(p.RichText)}}, nil
+	case *notionapi.NumberProperty:
+		return &notionapi.PropertyFilter{Property: propName, Number: &notionapi.NumberFilterCondition{Equals: &p.Number}}, nil
+	case *notionapi.SelectProperty:
+		return &notionapi.PropertyFilter{Property: propName, Select: &notionapi.SelectFilterCondition{Equals: p.Select.Name}}, nil
+	case *notionapi.StatusProperty:
+		return &notionapi.PropertyFilter{Property: propName, Status: &notionapi.StatusFilterCondition{Equals: p.Status.Name}}, nil
+	case *notionapi.CheckboxProperty:
+		// CheckboxFilterCondition.Equals is a plain bool tagged json:"omitempty",
+		// so a false value is silently dropped by encoding/json, turning the
+		// intended "equals false" filter into an empty, unconstrained one. There's
+		// no pointer or DoesNotEqual escape hatch on that condition, so checkbox
+		// keys can't be expressed reliably and are rejected instead.
+		return nil, fmt.Errorf("property %q of type %s cannot be used as an upsert key: notionapi.CheckboxFilterCondition.Equals can't represent a false value (omitempty drops it)", propName, prop.GetType())
+	case *notionapi.TitleProperty, *notionapi.URLProperty, *notionapi.EmailProperty, *notionapi.PhoneNumberProperty:
+		return nil, fmt.Errorf("property %q of type %s cannot be used as an upsert key: notionapi.PropertyFilter has no condition for this type", propName, prop.GetType())
+	default:
+		return nil, fmt.Errorf("property %q of type %s cannot be used as an upsert key", propName, prop.GetType())
+	}
+}
+
+// decodeNotionShape round-trips value through JSON into target, so callers
+// can accept either a raw Notion property object (the same shape the API
+// itself returns) or a partial shorthand struct for that property type.
+func decodeNotionShape(value interface{}, target interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, target)
+}
+
+// parseNotionDate parses a date or date-time string the same way the
+// notionapi.Date wire type does: RFC 3339 first, falling back to a bare
+// "2006-01-02" for date-only values.
+func parseNotionDate(s string) (*notionapi.Date, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		d := notionapi.Date(t)
+		return &d, nil
+	}
+
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil, err
+	}
+	d := notionapi.Date(t)
+	return &d, nil
+}
+
+// Helper functions for database inspection
+func getTitle(titleArray []notionapi.RichText) string {
+	if len(titleArray) == 0 {
+		return "Untitled"
+	}
+	
+	var title string
+	for _, text := range titleArray {
+		if text.Text != nil {
+			title += text.Text.Content
+		}
+	}
+	
+	return title
+}
+
+func getPropertyTypeString(prop notionapi.PropertyConfig) string {
+	switch prop.GetType() {
+	case notionapi.PropertyConfigTypeTitle:
+		return "Title"
 	case notionapi.PropertyConfigTypeRichText:
 		return "Rich Text"
 	case notionapi.PropertyConfigTypeNumber:
@@ -475,6 +1456,8 @@ func getPropertyTypeString(prop notionapi.PropertyConfig) string {
 		return "Relation"
 	case notionapi.PropertyConfigTypeRollup:
 		return "Rollup"
+	case notionapi.PropertyConfigStatus:
+		return "Status"
 	// Remove or comment out the undefined constants
 	// case notionapi.PropertyConfigTypeCreatedTime:
 	//	return "Created Time"
@@ -512,7 +1495,723 @@ func getSamplePropertyJSON(prop notionapi.PropertyConfig) string {
 		return `{"email": "example@example.com"}`
 	case notionapi.PropertyConfigTypePhoneNumber:
 		return `{"phone_number": "+1 234 567 8900"`
+	case notionapi.PropertyConfigTypeRelation:
+		return `{"relation": [{"id": "page-id-1"}, {"id": "page-id-2"}]}`
+	case notionapi.PropertyConfigStatus:
+		return `{"status": {"name": "In progress"}}`
 	default:
 		return `{/* Complex type - see Notion API docs */}`
 	}
-}
\ No newline at end of file
+}
+
+// insertRecord pairs a decoded record with its original source text, so
+// failures can be written back out verbatim via --failed-out.
+type insertRecord struct {
+	data   RawPageData
+	source string
+}
+
+// streamRawPageData decodes file according to format and returns a channel
+// of records plus a single-value error channel for a fatal read error. The
+// records channel is closed once the file is exhausted (or a fatal error is
+// hit), so callers can range over it. The csv and ndjson formats are read
+// record by record; the json format reads the whole file first (see streamJSON).
+func streamRawPageData(file *os.File, format string, schema notionapi.Database) (<-chan insertRecord, <-chan error) {
+	out := make(chan insertRecord)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errCh)
+
+		switch format {
+		case "csv":
+			errCh <- streamCSV(file, schema, out)
+		case "ndjson":
+			errCh <- streamNDJSON(file, out)
+		default:
+			errCh <- streamJSON(file, out)
+		}
+	}()
+
+	return out, errCh
+}
+
+// streamJSON preserves the existing insert behavior: the whole file is
+// decoded as either a JSON array or a single JSON object.
+func streamJSON(file *os.File, out chan<- insertRecord) error {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+
+	var rawData []RawPageData
+	if err := json.Unmarshal(content, &rawData); err != nil {
+		var single RawPageData
+		if errSingle := json.Unmarshal(content, &single); errSingle != nil {
+			return fmt.Errorf("invalid JSON data: %w", err)
+		}
+		rawData = []RawPageData{single}
+	}
+
+	for _, raw := range rawData {
+		source, _ := json.Marshal(raw)
+		out <- insertRecord{data: raw, source: string(source)}
+	}
+	return nil
+}
+
+// streamNDJSON decodes one JSON object per line, so the file is never fully
+// buffered in memory.
+func streamNDJSON(file *os.File, out chan<- insertRecord) error {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var raw RawPageData
+		if err := json.Unmarshal([]byte(line), &raw); err != nil {
+			return fmt.Errorf("line %d: invalid JSON: %w", lineNum, err)
+		}
+		out <- insertRecord{data: raw, source: line}
+	}
+	return scanner.Err()
+}
+
+// streamCSV reads the header row as property names and coerces each cell
+// against the database schema (numbers, booleans, dates, comma-split
+// multi-selects), so the result feeds convertToNotionProperties the same
+// way a hand-written JSON record would.
+func streamCSV(file *os.File, schema notionapi.Database, out chan<- insertRecord) error {
+	reader := csv.NewReader(file)
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	rowNum := 1
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("row %d: %w", rowNum, err)
+		}
+		rowNum++
+
+		props := make(map[string]interface{}, len(header))
+		for i, colName := range header {
+			if i >= len(row) {
+				continue
+			}
+			schemaProp, exists := schema.Properties[colName]
+			if !exists {
+				continue
+			}
+			props[colName] = csvCellToValue(schemaProp.GetType(), row[i])
+		}
+
+		raw := RawPageData{"properties": props}
+		source, _ := json.Marshal(raw)
+		out <- insertRecord{data: raw, source: string(source)}
+	}
+}
+
+// csvCellToValue coerces a raw CSV cell into the shorthand shape
+// convertPropertyValue expects for propType.
+func csvCellToValue(propType notionapi.PropertyConfigType, cell string) interface{} {
+	switch propType {
+	case notionapi.PropertyConfigTypeNumber:
+		if num, err := strconv.ParseFloat(cell, 64); err == nil {
+			return num
+		}
+		return cell
+	case notionapi.PropertyConfigTypeCheckbox:
+		if b, err := strconv.ParseBool(cell); err == nil {
+			return b
+		}
+		return cell
+	case notionapi.PropertyConfigTypeMultiSelect, notionapi.PropertyConfigTypeRelation, notionapi.PropertyConfigTypePeople:
+		if cell == "" {
+			return []interface{}{}
+		}
+		parts := strings.Split(cell, ",")
+		values := make([]interface{}, len(parts))
+		for i, part := range parts {
+			values[i] = strings.TrimSpace(part)
+		}
+		return values
+	default:
+		return cell
+	}
+}
+
+// batchRecords groups an incoming record stream into slices of size n (the
+// last batch may be shorter), closing the output channel once the source is
+// exhausted.
+func batchRecords(in <-chan insertRecord, n int) <-chan []insertRecord {
+	out := make(chan []insertRecord)
+
+	go func() {
+		defer close(out)
+
+		batch := make([]insertRecord, 0, n)
+		for rec := range in {
+			batch = append(batch, rec)
+			if len(batch) == n {
+				out <- batch
+				batch = make([]insertRecord, 0, n)
+			}
+		}
+		if len(batch) > 0 {
+			out <- batch
+		}
+	}()
+
+	return out
+}
+
+// rateLimiter is a simple token-bucket limiter used to keep insert concurrency
+// within Notion's documented request rate.
+type rateLimiter struct {
+	tokens chan struct{}
+}
+
+func newRateLimiter(requestsPerSecond int) *rateLimiter {
+	rl := &rateLimiter{tokens: make(chan struct{}, 1)}
+	rl.tokens <- struct{}{}
+
+	go func() {
+		ticker := time.NewTicker(time.Second / time.Duration(requestsPerSecond))
+		defer ticker.Stop()
+		for range ticker.C {
+			select {
+			case rl.tokens <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return rl
+}
+
+func (rl *rateLimiter) Wait(ctx context.Context) {
+	select {
+	case <-rl.tokens:
+	case <-ctx.Done():
+	}
+}
+
+// retryWithBackoff retries fn with exponential backoff when it fails with a
+// retryable Notion API error (HTTP 429 or 5xx), giving up after maxRetries
+// retries (maxRetries+1 calls of fn in all). The jomei/notionapi client already retries 429s internally using
+// the response's Retry-After header, and only returns a *notionapi.RateLimitedError
+// once its own retries are exhausted; the backoff below is what kicks in for
+// that case and for plain 5xx server errors.
+func retryWithBackoff(ctx context.Context, maxRetries int, fn func() error) error {
+	backoff := 500 * time.Millisecond
+
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		if !isRetryableNotionError(err) || attempt == maxRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+	return err
+}
+
+func isRetryableNotionError(err error) bool {
+	var rateLimitedErr *notionapi.RateLimitedError
+	if errors.As(err, &rateLimitedErr) {
+		return true
+	}
+
+	var apiErr *notionapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Status == 429 || apiErr.Status >= 500
+	}
+	return false
+}
+
+// failedRecordWriter writes one JSON line per failed record to --failed-out,
+// of the form {"row": <record as JSON>, "error": <message>}; the file is
+// truncated when opened. insert does not read this wrapped form as input;
+// each "row" value is a record in the shape the json and ndjson formats read.
+type failedRecordWriter struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func newFailedRecordWriter(path string) (*failedRecordWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &failedRecordWriter{file: file}, nil
+}
+
+func (w *failedRecordWriter) Write(source string, cause error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entry := struct {
+		Row   json.RawMessage `json:"row"`
+		Error string          `json:"error"`
+	}{
+		Row:   json.RawMessage(source),
+		Error: cause.Error(),
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	w.file.Write(append(line, '\n'))
+}
+
+func (w *failedRecordWriter) Close() error {
+	return w.file.Close()
+}
+
+// parseQueryFilter parses the JSON from --filter into a notionapi.PropertyFilter.
+// notionapi.Filter's marker method is unexported, so only concrete types from
+// the notionapi package itself (like PropertyFilter) can satisfy it here;
+// gotion can't pass an arbitrary JSON blob straight through, so --filter is
+// limited to a single property condition rather than an arbitrary and/or tree.
+func parseQueryFilter(data []byte) (notionapi.Filter, error) {
+	var f notionapi.PropertyFilter
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid --filter JSON: %w", err)
+	}
+	return &f, nil
+}
+
+// pagePropertiesToShorthand converts a page's Properties back into the same
+// shorthand JSON shape convertToNotionProperties accepts, so export and
+// insert round-trip.
+func pagePropertiesToShorthand(props notionapi.Properties) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(props))
+
+	for name, prop := range props {
+		value, err := shorthandFromProperty(prop)
+		if err != nil {
+			return nil, fmt.Errorf("property %q: %w", name, err)
+		}
+		result[name] = value
+	}
+
+	return result, nil
+}
+
+func shorthandFromProperty(prop notionapi.Property) (interface{}, error) {
+	switch p := prop.(type) {
+	case *notionapi.TitleProperty:
+		return getTitle(p.Title), nil
+	case *notionapi.RichTextProperty:
+		return getTitle(p.RichText), nil
+	case *notionapi.NumberProperty:
+		return p.Number, nil
+	case *notionapi.SelectProperty:
+		return p.Select.Name, nil
+	case *notionapi.StatusProperty:
+		return p.Status.Name, nil
+	case *notionapi.MultiSelectProperty:
+		names := make([]string, len(p.MultiSelect))
+		for i, option := range p.MultiSelect {
+			names[i] = option.Name
+		}
+		return names, nil
+	case *notionapi.DateProperty:
+		if p.Date == nil {
+			return nil, nil
+		}
+		start := time.Time(*p.Date.Start).Format(time.RFC3339)
+		if p.Date.End == nil {
+			return start, nil
+		}
+		return map[string]interface{}{
+			"start": start,
+			"end":   time.Time(*p.Date.End).Format(time.RFC3339),
+		}, nil
+	case *notionapi.PeopleProperty:
+		ids := make([]string, len(p.People))
+		for i, user := range p.People {
+			ids[i] = string(user.ID)
+		}
+		return ids, nil
+	case *notionapi.FilesProperty:
+		urls := make([]string, 0, len(p.Files))
+		for _, f := range p.Files {
+			if f.External != nil {
+				urls = append(urls, f.External.URL)
+			} else if f.File != nil {
+				urls = append(urls, f.File.URL)
+			}
+		}
+		return urls, nil
+	case *notionapi.CheckboxProperty:
+		return p.Checkbox, nil
+	case *notionapi.URLProperty:
+		return p.URL, nil
+	case *notionapi.EmailProperty:
+		return p.Email, nil
+	case *notionapi.PhoneNumberProperty:
+		return p.PhoneNumber, nil
+	case *notionapi.RelationProperty:
+		ids := make([]string, len(p.Relation))
+		for i, rel := range p.Relation {
+			ids[i] = string(rel.ID)
+		}
+		return ids, nil
+	default:
+		return nil, fmt.Errorf("unsupported property type %q for export", prop.GetType())
+	}
+}
+
+// csvHeaderFromShorthand picks a stable column order for a CSV export, since
+// Go map iteration order isn't.
+func csvHeaderFromShorthand(shorthand map[string]interface{}) []string {
+	header := make([]string, 0, len(shorthand))
+	for name := range shorthand {
+		header = append(header, name)
+	}
+	sort.Strings(header)
+	return header
+}
+
+// csvCellFromShorthand flattens a shorthand property value into a single CSV
+// cell, mirroring the comma-joined convention streamCSV reads back in.
+func csvCellFromShorthand(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// fetchAllChildren pages through every child block of blockID via
+// Block.GetChildren until has_more is false.
+func fetchAllChildren(ctx context.Context, client *notionapi.Client, blockID notionapi.BlockID) ([]notionapi.Block, error) {
+	var blocks []notionapi.Block
+	cursor := notionapi.Cursor("")
+
+	for {
+		resp, err := client.Block.GetChildren(ctx, blockID, &notionapi.Pagination{
+			StartCursor: cursor,
+			PageSize:    100,
+		})
+		if err != nil {
+			return blocks, err
+		}
+
+		blocks = append(blocks, resp.Results...)
+
+		if !resp.HasMore {
+			return blocks, nil
+		}
+		cursor = notionapi.Cursor(resp.NextCursor)
+	}
+}
+
+// renderPageMarkdown renders a page's title, properties, and content blocks
+// as a single Markdown document, mirroring the Notion-as-headless-CMS pattern.
+func renderPageMarkdown(page notionapi.Page, blocks []notionapi.Block) string {
+	var b strings.Builder
+
+	title := "Untitled"
+	for name, prop := range page.Properties {
+		if titleProp, ok := prop.(*notionapi.TitleProperty); ok {
+			title = getTitle(titleProp.Title)
+			_ = name
+			break
+		}
+	}
+
+	fmt.Fprintf(&b, "# %s\n\n", title)
+
+	for name, prop := range page.Properties {
+		if _, ok := prop.(*notionapi.TitleProperty); ok {
+			continue
+		}
+		value, err := shorthandFromProperty(prop)
+		if err != nil || value == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "- **%s**: %v\n", name, value)
+	}
+	b.WriteString("\n")
+
+	for _, block := range blocks {
+		b.WriteString(renderBlockMarkdown(block))
+	}
+
+	return b.String()
+}
+
+// renderBlockMarkdown renders a single Notion block as Markdown. Unsupported
+// block types are skipped rather than aborting the whole export.
+func renderBlockMarkdown(block notionapi.Block) string {
+	switch blk := block.(type) {
+	case *notionapi.Heading1Block:
+		return fmt.Sprintf("# %s\n\n", getTitle(blk.Heading1.RichText))
+	case *notionapi.Heading2Block:
+		return fmt.Sprintf("## %s\n\n", getTitle(blk.Heading2.RichText))
+	case *notionapi.Heading3Block:
+		return fmt.Sprintf("### %s\n\n", getTitle(blk.Heading3.RichText))
+	case *notionapi.ParagraphBlock:
+		return fmt.Sprintf("%s\n\n", getTitle(blk.Paragraph.RichText))
+	case *notionapi.BulletedListItemBlock:
+		return fmt.Sprintf("- %s\n", getTitle(blk.BulletedListItem.RichText))
+	case *notionapi.NumberedListItemBlock:
+		return fmt.Sprintf("1. %s\n", getTitle(blk.NumberedListItem.RichText))
+	case *notionapi.ToDoBlock:
+		checkbox := " "
+		if blk.ToDo.Checked {
+			checkbox = "x"
+		}
+		return fmt.Sprintf("- [%s] %s\n", checkbox, getTitle(blk.ToDo.RichText))
+	case *notionapi.CodeBlock:
+		return fmt.Sprintf("```%s\n%s\n```\n\n", blk.Code.Language, getTitle(blk.Code.RichText))
+	default:
+		return ""
+	}
+}
+
+// curlScriptBuilder accumulates the curl invocations a --dry-run should have
+// made, and writes them out as a single reviewable shell script.
+type curlScriptBuilder struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (b *curlScriptBuilder) addRequest(method, path string, body interface{}) {
+	var bodyJSON []byte
+	if body != nil {
+		bodyJSON, _ = json.MarshalIndent(body, "", "  ")
+	}
+
+	var cmd strings.Builder
+	fmt.Fprintf(&cmd, "curl -sS -X %s \\\n", method)
+	fmt.Fprintf(&cmd, "  \"%s%s\" \\\n", notionAPIBaseURL, path)
+	fmt.Fprintf(&cmd, "  -H \"Authorization: Bearer $NOTION_API_KEY\" \\\n")
+	fmt.Fprintf(&cmd, "  -H \"Notion-Version: %s\" \\\n", notionAPIVersion)
+	cmd.WriteString("  -H \"Content-Type: application/json\"")
+	if len(bodyJSON) > 0 {
+		escaped := strings.ReplaceAll(string(bodyJSON), "'", `'\''`)
+		fmt.Fprintf(&cmd, " \\\n  --data '%s'", escaped)
+	}
+
+	b.mu.Lock()
+	b.requests = append(b.requests, cmd.String())
+	b.mu.Unlock()
+}
+
+func (b *curlScriptBuilder) AddPageCreate(req *notionapi.PageCreateRequest) {
+	b.addRequest("POST", "/pages", req)
+}
+
+func (b *curlScriptBuilder) AddPageUpdate(pageID notionapi.PageID, req *notionapi.PageUpdateRequest) {
+	b.addRequest("PATCH", "/pages/"+string(pageID), req)
+}
+
+func (b *curlScriptBuilder) AddDatabaseQuery(dbID notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest) {
+	b.addRequest("POST", "/databases/"+string(dbID)+"/query", req)
+}
+
+// WriteScript renders the accumulated requests as an executable bash script
+// and writes it to path (or stdout when path is empty).
+func (b *curlScriptBuilder) WriteScript(path, dbID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var out io.Writer = os.Stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	fmt.Fprintln(out, "#!/usr/bin/env bash")
+	fmt.Fprintln(out, "set -euo pipefail")
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "# gotion %s dry run\n", gotionVersion)
+	fmt.Fprintf(out, "# Database: %s\n", dbID)
+	fmt.Fprintf(out, "# Requests: %d\n", len(b.requests))
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, `: "${NOTION_API_KEY:?NOTION_API_KEY must be set}"`)
+	fmt.Fprintln(out)
+
+	for _, req := range b.requests {
+		fmt.Fprintln(out, req)
+		fmt.Fprintln(out)
+	}
+
+	if path != "" {
+		return os.Chmod(path, 0755)
+	}
+	return nil
+}
+
+// sampleShorthandForProperty builds a realistic example value for a schema
+// property, in the same shorthand shape convertPropertyValue accepts. Relation
+// values are placeholders, not real page IDs, and property types with no case
+// here (formula, rollup and the like) get nil, which convertPropertyValue
+// rejects.
+func sampleShorthandForProperty(prop notionapi.PropertyConfig) interface{} {
+	switch p := prop.(type) {
+	case *notionapi.TitlePropertyConfig:
+		return "Sample Title"
+	case *notionapi.RichTextPropertyConfig:
+		return "Sample text"
+	case *notionapi.NumberPropertyConfig:
+		return 42
+	case *notionapi.SelectPropertyConfig:
+		if len(p.Select.Options) > 0 {
+			return p.Select.Options[0].Name
+		}
+		return "Option Name"
+	case *notionapi.MultiSelectPropertyConfig:
+		if len(p.MultiSelect.Options) > 0 {
+			names := make([]string, 0, 2)
+			for i, option := range p.MultiSelect.Options {
+				if i >= 2 {
+					break
+				}
+				names = append(names, option.Name)
+			}
+			return names
+		}
+		return []string{"Option 1", "Option 2"}
+	case *notionapi.StatusPropertyConfig:
+		if len(p.Status.Options) > 0 {
+			return p.Status.Options[0].Name
+		}
+		return "Not started"
+	case *notionapi.DatePropertyConfig:
+		return "2023-01-01"
+	case *notionapi.PeoplePropertyConfig:
+		return []string{}
+	case *notionapi.FilesPropertyConfig:
+		return []string{"https://example.com/file.pdf"}
+	case *notionapi.CheckboxPropertyConfig:
+		return true
+	case *notionapi.URLPropertyConfig:
+		return "https://example.com"
+	case *notionapi.EmailPropertyConfig:
+		return "name@example.com"
+	case *notionapi.PhoneNumberPropertyConfig:
+		return "+1 234 567 8900"
+	case *notionapi.RelationPropertyConfig:
+		return []string{fmt.Sprintf("<page-id-from-database-%s>", p.Relation.DatabaseID)}
+	default:
+		return nil
+	}
+}
+
+// validatePageData runs the same per-property conversion
+// convertToNotionProperties does, collecting every conversion error, and
+// additionally flags properties that aren't defined in the schema at all or whose
+// select/multi-select/status values aren't one of the configured options.
+func validatePageData(raw RawPageData, schema notionapi.Database) ConversionErrors {
+	var errs ConversionErrors
+
+	props, ok := raw["properties"].(map[string]interface{})
+	if !ok {
+		return ConversionErrors{{Property: "properties", Err: fmt.Errorf("expected key 'properties' in data, got none")}}
+	}
+
+	for propName, propValue := range props {
+		schemaProp, exists := schema.Properties[propName]
+		if !exists {
+			errs = append(errs, &PropertyConversionError{Property: propName, Err: fmt.Errorf("not defined in the database schema")})
+			continue
+		}
+
+		notionProp, err := convertPropertyValue(schemaProp.GetType(), propValue)
+		if err != nil {
+			errs = append(errs, &PropertyConversionError{Property: propName, Err: err})
+			continue
+		}
+
+		if err := validateOptionValue(schemaProp, notionProp); err != nil {
+			errs = append(errs, &PropertyConversionError{Property: propName, Err: err})
+		}
+	}
+
+	return errs
+}
+
+// validateOptionValue checks that a converted Select/MultiSelect/Status
+// property's value is one of the options the database schema defines.
+func validateOptionValue(schemaProp notionapi.PropertyConfig, prop notionapi.Property) error {
+	switch p := prop.(type) {
+	case *notionapi.SelectProperty:
+		cfg, ok := schemaProp.(*notionapi.SelectPropertyConfig)
+		if !ok || p.Select.Name == "" {
+			return nil
+		}
+		if !hasOption(cfg.Select.Options, p.Select.Name) {
+			return fmt.Errorf("select option %q is not one of the database's configured options", p.Select.Name)
+		}
+	case *notionapi.MultiSelectProperty:
+		cfg, ok := schemaProp.(*notionapi.MultiSelectPropertyConfig)
+		if !ok {
+			return nil
+		}
+		for _, option := range p.MultiSelect {
+			if !hasOption(cfg.MultiSelect.Options, option.Name) {
+				return fmt.Errorf("multi_select option %q is not one of the database's configured options", option.Name)
+			}
+		}
+	case *notionapi.StatusProperty:
+		cfg, ok := schemaProp.(*notionapi.StatusPropertyConfig)
+		if !ok || p.Status.Name == "" {
+			return nil
+		}
+		if !hasOption(cfg.Status.Options, p.Status.Name) {
+			return fmt.Errorf("status option %q is not one of the database's configured options", p.Status.Name)
+		}
+	}
+	return nil
+}
+
+func hasOption(options []notionapi.Option, name string) bool {
+	for _, option := range options {
+		if option.Name == name {
+			return true
+		}
+	}
+	return false
+}